@@ -0,0 +1,100 @@
+package main
+
+import "time"
+
+// UnitSystem определяет, в каких единицах InfoMessage отображает
+// дистанцию и скорость.
+type UnitSystem int
+
+// Поддерживаемые системы единиц.
+const (
+	Metric UnitSystem = iota
+	Imperial
+)
+
+// Коэффициенты перевода для отображения в имперской системе.
+const (
+	KmToMiles = 0.621371
+)
+
+// CalorieInputs — величины, которые может использовать формула расчёта
+// калорий. Конкретная модель использует лишь нужные ей поля.
+type CalorieInputs struct {
+	MeanSpeed float64 // средняя скорость, км/ч
+	Weight    float64 // вес пользователя, кг
+	Height    float64 // рост пользователя, см (0, если неприменимо)
+	Duration  time.Duration
+}
+
+// CalorieModel вычисляет потраченные калории по входным данным
+// тренировки. Регистрируется в реестре под ключом "тип/имя", например
+// "running/acsm" или "walking/minetti".
+type CalorieModel interface {
+	Calories(in CalorieInputs) float64
+}
+
+// defaultModelName — имя формул, унаследованных от исходных констант
+// пакета (CaloriesMeanSpeedMultiplier и т.д.), используемое когда
+// Training.Model не задан.
+const defaultModelName = "sports-academy"
+
+var calorieModels = map[string]CalorieModel{}
+
+// RegisterModel регистрирует модель расчёта калорий под ключом
+// "тип/имя" (например "running/pandolf"), чтобы она могла быть выбрана
+// через поле Training.Model.
+func RegisterModel(key string, m CalorieModel) {
+	calorieModels[key] = m
+}
+
+// lookupModel возвращает модель, выбранную для дисциплины discipline
+// ("running", "walking", "swimming") и имени name, либо модель по
+// умолчанию, если имя не задано или не зарегистрировано.
+func lookupModel(discipline, name string) CalorieModel {
+	if name == "" {
+		name = defaultModelName
+	}
+	if m, ok := calorieModels[discipline+"/"+name]; ok {
+		return m
+	}
+	return calorieModels[discipline+"/"+defaultModelName]
+}
+
+func init() {
+	RegisterModel("running/"+defaultModelName, sportsAcademyRunningModel{})
+	RegisterModel("walking/"+defaultModelName, sportsAcademyWalkingModel{})
+	RegisterModel("swimming/"+defaultModelName, sportsAcademySwimmingModel{})
+}
+
+// sportsAcademyRunningModel — формула расчёта калорий для бега,
+// использовавшаяся до появления реестра моделей.
+type sportsAcademyRunningModel struct{}
+
+func (sportsAcademyRunningModel) Calories(in CalorieInputs) float64 {
+	return (CaloriesMeanSpeedMultiplier*in.MeanSpeed + CaloriesMeanSpeedShift) *
+		in.Weight / MInKm * in.Duration.Hours() * MinsInHour
+}
+
+// sportsAcademyWalkingModel — формула расчёта калорий для ходьбы,
+// использовавшаяся до появления реестра моделей.
+type sportsAcademyWalkingModel struct{}
+
+func (sportsAcademyWalkingModel) Calories(in CalorieInputs) float64 {
+	heightInMeters := in.Height / CmInM
+	if heightInMeters == 0 {
+		return 0
+	}
+	speedInMSec := in.MeanSpeed * KmHInMsec
+	return (CaloriesWeightMultiplier*in.Weight +
+		(speedInMSec*speedInMSec/heightInMeters)*CaloriesSpeedHeightMultiplier*in.Weight) *
+		in.Duration.Hours() * MinsInHour
+}
+
+// sportsAcademySwimmingModel — формула расчёта калорий для плавания,
+// использовавшаяся до появления реестра моделей.
+type sportsAcademySwimmingModel struct{}
+
+func (sportsAcademySwimmingModel) Calories(in CalorieInputs) float64 {
+	return (in.MeanSpeed + SwimmingCaloriesMeanSpeedShift) *
+		SwimmingCaloriesWeightMultiplier * in.Weight * in.Duration.Hours()
+}
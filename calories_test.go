@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fixedCalorieModel always returns the same value, regardless of input —
+// used to prove a registered model was actually selected over the default.
+type fixedCalorieModel struct{ value float64 }
+
+func (m fixedCalorieModel) Calories(CalorieInputs) float64 { return m.value }
+
+func TestTrainingModelSelectsRegisteredOverDefault(t *testing.T) {
+	RegisterModel("running/test-fixed", fixedCalorieModel{value: 123.45})
+
+	r := Running{Training: Training{
+		TrainingType: "Бег",
+		Action:       5000,
+		LenStep:      LenStep,
+		Duration:     30 * time.Minute,
+		Weight:       80,
+		Model:        "test-fixed",
+	}}
+	if got := r.Calories(); got != 123.45 {
+		t.Errorf("Calories() = %v, want 123.45 from the registered model", got)
+	}
+
+	// Without Model set, the default sports-academy formula applies and
+	// differs from the fixed value.
+	def := Running{Training: Training{
+		TrainingType: "Бег",
+		Action:       5000,
+		LenStep:      LenStep,
+		Duration:     30 * time.Minute,
+		Weight:       80,
+	}}
+	if got := def.Calories(); got == 123.45 {
+		t.Errorf("default Calories() unexpectedly matches the registered fixed model's value")
+	}
+}
+
+func TestLookupModelFallsBackToDefault(t *testing.T) {
+	want := calorieModels["running/"+defaultModelName]
+
+	if got := lookupModel("running", ""); got != want {
+		t.Errorf("lookupModel(running, \"\") = %v, want default model %v", got, want)
+	}
+	if got := lookupModel("running", "does-not-exist"); got != want {
+		t.Errorf("lookupModel(running, unknown) = %v, want default model %v", got, want)
+	}
+}
+
+func TestInfoMessageStringImperialUnits(t *testing.T) {
+	info := InfoMessage{
+		TrainingType: "Бег",
+		Duration:     30 * time.Minute,
+		Distance:     10,
+		Speed:        20,
+		Calories:     300,
+		HasDistance:  true,
+		Units:        Imperial,
+	}
+	s := info.String()
+
+	wantDistance := 10 * KmToMiles
+	wantSpeed := 20 * KmToMiles
+	if !strings.Contains(s, "миль") {
+		t.Errorf("String() = %q, want imperial unit labels", s)
+	}
+	if !strings.Contains(s, fmt.Sprintf("%.2f", wantDistance)) {
+		t.Errorf("String() = %q, want converted distance %.2f", s, wantDistance)
+	}
+	if !strings.Contains(s, fmt.Sprintf("%.2f", wantSpeed)) {
+		t.Errorf("String() = %q, want converted speed %.2f", s, wantSpeed)
+	}
+	if math.Abs(wantDistance-6.21371) > 1e-5 {
+		t.Fatalf("sanity check failed: %.5f", wantDistance)
+	}
+}
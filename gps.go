@@ -0,0 +1,128 @@
+package main
+
+import (
+	"math"
+	"time"
+)
+
+// TrackPoint — одна точка GPS-трека тренировки.
+type TrackPoint struct {
+	Lat, Lon float64
+	Ele      float64
+	Time     time.Time
+	HR       *int
+	Cadence  *int
+}
+
+// GPSTrack — последовательность точек GPS-трека, по которой можно
+// восстановить дистанцию, длительность и число шагов тренировки.
+type GPSTrack []TrackPoint
+
+// metersPerDegreeLat — метров в одном градусе широты; используется для
+// плоского приближения расстояния на коротких отрезках.
+const metersPerDegreeLat = 111319.9
+
+// shortSegmentMeters — порог, ниже которого плоское приближение
+// метров-на-градус точнее/дешевле, чем подходит для разницы с полным
+// гаверсинусом; выше него кривизна Земли уже значима.
+const shortSegmentMeters = 500.0
+
+const earthRadiusMeters = 6371000.0
+
+// flatSegmentMeters — приближённое расстояние между близкими точками
+// через metersPerDegreeLat и metersPerDegreeLng = metersPerDegreeLat*cos(lat).
+func flatSegmentMeters(a, b TrackPoint) float64 {
+	avgLatRad := (a.Lat + b.Lat) / 2 * math.Pi / 180
+	metersPerDegreeLng := metersPerDegreeLat * math.Cos(avgLatRad)
+	dy := (b.Lat - a.Lat) * metersPerDegreeLat
+	dx := (b.Lon - a.Lon) * metersPerDegreeLng
+	return math.Hypot(dx, dy)
+}
+
+// haversineMeters — точное расстояние по формуле гаверсинусов, для
+// отрезков, на которых плоское приближение накапливает заметную ошибку.
+func haversineMeters(a, b TrackPoint) float64 {
+	lat1, lon1 := a.Lat*math.Pi/180, a.Lon*math.Pi/180
+	lat2, lon2 := b.Lat*math.Pi/180, b.Lon*math.Pi/180
+	dLat := lat2 - lat1
+	dLon := lon2 - lon1
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusMeters * math.Asin(math.Sqrt(h))
+}
+
+// segmentMeters выбирает плоское приближение для коротких отрезков и
+// полный гаверсинус для длинных.
+func segmentMeters(a, b TrackPoint) float64 {
+	d := flatSegmentMeters(a, b)
+	if d <= shortSegmentMeters {
+		return d
+	}
+	return haversineMeters(a, b)
+}
+
+// Distance возвращает суммарную дистанцию трека в километрах.
+func (tr GPSTrack) Distance() float64 {
+	var total float64
+	for i := 1; i < len(tr); i++ {
+		total += segmentMeters(tr[i-1], tr[i])
+	}
+	return total / MInKm
+}
+
+// Duration возвращает длительность трека по первой и последней
+// временным отметкам точек.
+func (tr GPSTrack) Duration() time.Duration {
+	if len(tr) == 0 {
+		return 0
+	}
+	return tr[len(tr)-1].Time.Sub(tr[0].Time)
+}
+
+// action интегрирует каденс по времени между соседними точками,
+// возвращая оценку числа шагов/гребков за весь трек.
+func (tr GPSTrack) action() int {
+	var steps float64
+	for i := 1; i < len(tr); i++ {
+		if tr[i-1].Cadence == nil {
+			continue
+		}
+		dt := tr[i].Time.Sub(tr[i-1].Time).Minutes()
+		steps += float64(*tr[i-1].Cadence) * dt
+	}
+	return int(steps + 0.5)
+}
+
+// NewRunningFromTrack строит тренировку Running по GPS-треку: дистанция
+// берётся из трека (distanceOverride), длительность и число шагов — из
+// временных меток и каденса точек.
+func NewRunningFromTrack(tr GPSTrack, weight float64) Running {
+	dist := tr.Distance()
+	return Running{
+		Training: Training{
+			TrainingType:     "Бег",
+			Action:           tr.action(),
+			LenStep:          LenStep,
+			Duration:         tr.Duration(),
+			Weight:           weight,
+			distanceOverride: &dist,
+		},
+	}
+}
+
+// NewWalkingFromTrack строит тренировку Walking по GPS-треку, аналогично
+// NewRunningFromTrack.
+func NewWalkingFromTrack(tr GPSTrack, weight, height float64) Walking {
+	dist := tr.Distance()
+	return Walking{
+		Training: Training{
+			TrainingType:     "Ходьба",
+			Action:           tr.action(),
+			LenStep:          LenStep,
+			Duration:         tr.Duration(),
+			Weight:           weight,
+			distanceOverride: &dist,
+		},
+		Height: height,
+	}
+}
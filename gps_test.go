@@ -0,0 +1,79 @@
+package main
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestGPSTrackDistanceShortSegment(t *testing.T) {
+	// ~0.001 degree of latitude at the equator is about 111.3 m, well
+	// under shortSegmentMeters, so the flat approximation is used.
+	track := GPSTrack{
+		{Lat: 0, Lon: 0},
+		{Lat: 0.001, Lon: 0},
+	}
+	got := track.Distance() * MInKm // meters
+	want := metersPerDegreeLat * 0.001
+	if math.Abs(got-want) > 1 {
+		t.Errorf("Distance() = %.3f m, want ~%.3f m", got, want)
+	}
+}
+
+func TestGPSTrackDistanceLongSegment(t *testing.T) {
+	// ~0.1 degree of latitude (~11 km) is above shortSegmentMeters, so the
+	// full Haversine formula is used; it should agree closely with the
+	// flat approximation at this latitude.
+	track := GPSTrack{
+		{Lat: 10, Lon: 10},
+		{Lat: 10.1, Lon: 10},
+	}
+	got := track.Distance() * MInKm
+	want := flatSegmentMeters(track[0], track[1])
+	if math.Abs(got-want)/want > 0.01 {
+		t.Errorf("Distance() = %.3f m, want close to flat approximation %.3f m", got, want)
+	}
+}
+
+func TestGPSTrackDurationAndAction(t *testing.T) {
+	base := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	cad1, cad2 := 170, 180
+	track := GPSTrack{
+		{Time: base, Cadence: &cad1},
+		{Time: base.Add(time.Minute), Cadence: &cad2},
+		{Time: base.Add(2 * time.Minute)},
+	}
+	if got, want := track.Duration(), 2*time.Minute; got != want {
+		t.Errorf("Duration() = %v, want %v", got, want)
+	}
+	// action() integrates cadence over the interval that follows each
+	// sample: 170 steps/min for 1 minute, then 180 steps/min for 1 minute.
+	if got, want := track.action(), 170+180; got != want {
+		t.Errorf("action() = %d, want %d", got, want)
+	}
+}
+
+func TestNewRunningFromTrackUsesGPSDistance(t *testing.T) {
+	base := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	cad := 170
+	track := GPSTrack{
+		{Lat: 0, Lon: 0, Time: base, Cadence: &cad},
+		{Lat: 0.01, Lon: 0, Time: base.Add(10 * time.Minute)},
+	}
+
+	r := NewRunningFromTrack(track, 80)
+	if r.Duration != 10*time.Minute {
+		t.Errorf("Duration = %v, want 10m", r.Duration)
+	}
+
+	info := r.TrainingInfo()
+	trackDist := track.Distance()
+	if math.Abs(info.Distance-trackDist) > 1e-9 {
+		t.Errorf("TrainingInfo().Distance = %v, want GPS-measured %v (not Action*LenStep)", info.Distance, trackDist)
+	}
+	// Action*LenStep would give a very different number, proving the
+	// override actually takes priority over the step-counted distance.
+	if naive := float64(r.Action) * r.LenStep / MInKm; math.Abs(info.Distance-naive) < 1e-9 {
+		t.Errorf("TrainingInfo().Distance unexpectedly matches the naive Action*LenStep distance")
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"math"
+	"time"
+)
+
+// HRSample — одно измерение пульса во время тренировки.
+type HRSample struct {
+	T   time.Time
+	BPM int
+}
+
+// HRZones — пороги пульса атлета, относительно которых считается резерв
+// пульса (HRR) для зон и TRIMP.
+type HRZones struct {
+	Resting int
+	Max     int
+}
+
+// Sex — пол атлета, влияет на коэффициенты TRIMP и CaloriesFromHR.
+type Sex int
+
+// Поддерживаемые значения Sex.
+const (
+	Male Sex = iota
+	Female
+)
+
+// hrr возвращает резерв частоты пульса (0..1) для заданного BPM
+// относительно зон атлета, по формуле Карвонена.
+func (t Training) hrr(bpm int) float64 {
+	span := t.Zones.Max - t.Zones.Resting
+	if span <= 0 {
+		return 0
+	}
+	v := float64(bpm-t.Zones.Resting) / float64(span)
+	switch {
+	case v < 0:
+		return 0
+	case v > 1:
+		return 1
+	default:
+		return v
+	}
+}
+
+// TimeInZones возвращает время, проведённое в пяти классических зонах
+// %HRR: 50-60, 60-70, 70-80, 80-90, 90-100.
+func (t Training) TimeInZones() [5]time.Duration {
+	var zones [5]time.Duration
+	for i := 1; i < len(t.HRSamples); i++ {
+		dt := t.HRSamples[i].T.Sub(t.HRSamples[i-1].T)
+		if dt <= 0 {
+			continue
+		}
+		hrr := t.hrr(t.HRSamples[i-1].BPM)
+		if hrr < 0.5 {
+			continue
+		}
+		idx := int((hrr - 0.5) / 0.1)
+		if idx > 4 {
+			idx = 4
+		}
+		zones[idx] += dt
+	}
+	return zones
+}
+
+// TRIMP вычисляет экспоненциальный TRIMP Банистера: сумму по интервалам
+// между соседними замерами пульса от Δt_min * HRR * весовой коэффициент,
+// где коэффициент — 0.64*exp(1.92*HRR) для мужчин и 0.86*exp(1.67*HRR)
+// для женщин.
+func (t Training) TRIMP() float64 {
+	var total float64
+	for i := 1; i < len(t.HRSamples); i++ {
+		dtMin := t.HRSamples[i].T.Sub(t.HRSamples[i-1].T).Minutes()
+		if dtMin <= 0 {
+			continue
+		}
+		hrr := t.hrr(t.HRSamples[i-1].BPM)
+		var weight float64
+		if t.Sex == Female {
+			weight = 0.86 * math.Exp(1.67*hrr)
+		} else {
+			weight = 0.64 * math.Exp(1.92*hrr)
+		}
+		total += dtMin * hrr * weight
+	}
+	return total
+}
+
+// CaloriesFromHR оценивает потраченные калории по уравнению Кейтела на
+// основе среднего пульса тренировки, веса и возраста атлета — отдельно
+// от механической формулы Calories(), чтобы их можно было сравнить.
+func (t Training) CaloriesFromHR() float64 {
+	if len(t.HRSamples) == 0 {
+		return 0
+	}
+	var sumBPM int
+	for _, s := range t.HRSamples {
+		sumBPM += s.BPM
+	}
+	meanBPM := float64(sumBPM) / float64(len(t.HRSamples))
+	durMin := t.Duration.Minutes()
+	age := float64(t.Age)
+	if t.Sex == Female {
+		return (-20.4022 + 0.4472*meanBPM - 0.1263*t.Weight + 0.074*age) / 4.184 * durMin
+	}
+	return (-55.0969 + 0.6309*meanBPM + 0.1988*t.Weight + 0.2017*age) / 4.184 * durMin
+}
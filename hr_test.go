@@ -0,0 +1,81 @@
+package main
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func sampleTraining(sex Sex) Training {
+	base := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	return Training{
+		Weight: 80,
+		Age:    30,
+		Sex:    sex,
+		Zones:  HRZones{Resting: 60, Max: 180},
+		HRSamples: []HRSample{
+			{T: base, BPM: 120},                       // HRR = 0.50
+			{T: base.Add(10 * time.Minute), BPM: 150}, // HRR = 0.75
+			{T: base.Add(20 * time.Minute), BPM: 150},
+		},
+		Duration: 20 * time.Minute,
+	}
+}
+
+func TestTrainingTimeInZones(t *testing.T) {
+	tr := sampleTraining(Male)
+	zones := tr.TimeInZones()
+
+	// Interval 1 (120 bpm, HRR=0.50) falls in zone 0 (50-60% HRR).
+	if zones[0] != 10*time.Minute {
+		t.Errorf("zones[0] = %v, want 10m", zones[0])
+	}
+	// Interval 2 (150 bpm, HRR=0.75) falls in zone 2 (70-80% HRR).
+	if zones[2] != 10*time.Minute {
+		t.Errorf("zones[2] = %v, want 10m", zones[2])
+	}
+	for _, idx := range []int{1, 3, 4} {
+		if zones[idx] != 0 {
+			t.Errorf("zones[%d] = %v, want 0", idx, zones[idx])
+		}
+	}
+}
+
+func TestTrainingTRIMPDiffersBySex(t *testing.T) {
+	male := sampleTraining(Male).TRIMP()
+	female := sampleTraining(Female).TRIMP()
+	if male <= 0 || female <= 0 {
+		t.Fatalf("TRIMP() male=%v female=%v, want both > 0", male, female)
+	}
+	if male == female {
+		t.Errorf("TRIMP() male and female coefficients should not produce the same value")
+	}
+}
+
+func TestTrainingCaloriesFromHR(t *testing.T) {
+	tr := sampleTraining(Male)
+	meanBPM := (120.0 + 150.0 + 150.0) / 3.0
+	want := (-55.0969 + 0.6309*meanBPM + 0.1988*tr.Weight + 0.2017*float64(tr.Age)) / 4.184 * tr.Duration.Minutes()
+
+	got := tr.CaloriesFromHR()
+	if math.Abs(got-want) > 1e-6 {
+		t.Errorf("CaloriesFromHR() = %v, want %v", got, want)
+	}
+
+	female := sampleTraining(Female)
+	femaleWant := (-20.4022 + 0.4472*meanBPM - 0.1263*female.Weight + 0.074*float64(female.Age)) / 4.184 * female.Duration.Minutes()
+	if got := female.CaloriesFromHR(); math.Abs(got-femaleWant) > 1e-6 {
+		t.Errorf("female CaloriesFromHR() = %v, want %v", got, femaleWant)
+	}
+}
+
+func TestTrainingInfoIncludesCaloriesFromHR(t *testing.T) {
+	r := Running{Training: sampleTraining(Male)}
+	info := r.TrainingInfo()
+	if !info.HasHR {
+		t.Fatal("InfoMessage.HasHR = false, want true for a training with HRSamples")
+	}
+	if info.CaloriesFromHR != r.CaloriesFromHR() {
+		t.Errorf("InfoMessage.CaloriesFromHR = %v, want %v", info.CaloriesFromHR, r.CaloriesFromHR())
+	}
+}
@@ -0,0 +1,511 @@
+package main
+
+import (
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Значения профиля пользователя по умолчанию, используемые, когда файл
+// тренировки не сообщает вес, рост или длину бассейна явно.
+const (
+	DefaultWeight     = 70.0  // кг
+	DefaultHeight     = 175.0 // см
+	DefaultPoolLength = 25    // м
+)
+
+// trackSample — точка трека в промежуточном представлении, общем для
+// GPX, TCX и FIT, до преобразования в конкретный тип тренировки.
+type trackSample struct {
+	Time        time.Time
+	HasTime     bool
+	Cadence     int
+	HasCadence  bool
+	Distance    float64 // метры, если сообщается файлом
+	HasDistance bool
+}
+
+// profileExtensions — необязательные сведения об атлете и снаряжении,
+// которые формат может нести вместе с треком (вес, рост, длина дорожки
+// бассейна для заплывов).
+type profileExtensions struct {
+	Weight     float64 `xml:"Weight"`
+	Height     float64 `xml:"Height"`
+	PoolLength float64 `xml:"PoolLength"`
+}
+
+// buildTrainings превращает набор точек трека и профиль атлета в одну или
+// несколько тренировок нужного типа. kind принимает значения "running",
+// "walking" и "swimming"; при отсутствии совпадения используется "running".
+func buildTrainings(kind string, samples []trackSample, prof profileExtensions) ([]CaloriesCalculator, error) {
+	if len(samples) == 0 {
+		return nil, fmt.Errorf("importer: трек не содержит точек")
+	}
+
+	duration := trackDuration(samples)
+	action := trackAction(samples, duration)
+	weight := prof.Weight
+	if weight == 0 {
+		weight = DefaultWeight
+	}
+
+	switch {
+	case strings.Contains(kind, "walk"):
+		height := prof.Height
+		if height == 0 {
+			height = DefaultHeight
+		}
+		return []CaloriesCalculator{Walking{
+			Training: Training{
+				TrainingType: "Ходьба",
+				Action:       action,
+				LenStep:      LenStep,
+				Duration:     duration,
+				Weight:       weight,
+			},
+			Height: height,
+		}}, nil
+	case strings.Contains(kind, "swim"):
+		poolLength := int(prof.PoolLength)
+		if poolLength == 0 {
+			poolLength = DefaultPoolLength
+		}
+		totalDistance := trackDistance(samples)
+		countPool := 0
+		if poolLength > 0 {
+			countPool = int(totalDistance/float64(poolLength) + 0.5)
+		}
+		return []CaloriesCalculator{Swimming{
+			Training: Training{
+				TrainingType: "Плавание",
+				Action:       action,
+				LenStep:      SwimmingLenStep,
+				Duration:     duration,
+				Weight:       weight,
+			},
+			LengthPool: poolLength,
+			CountPool:  countPool,
+		}}, nil
+	default:
+		return []CaloriesCalculator{Running{
+			Training: Training{
+				TrainingType: "Бег",
+				Action:       action,
+				LenStep:      LenStep,
+				Duration:     duration,
+				Weight:       weight,
+			},
+		}}, nil
+	}
+}
+
+// trackDuration вычисляет длительность тренировки по первой и последней
+// временной отметке трека.
+func trackDuration(samples []trackSample) time.Duration {
+	var first, last time.Time
+	found := false
+	for _, s := range samples {
+		if !s.HasTime {
+			continue
+		}
+		if !found {
+			first, last = s.Time, s.Time
+			found = true
+			continue
+		}
+		if s.Time.Before(first) {
+			first = s.Time
+		}
+		if s.Time.After(last) {
+			last = s.Time
+		}
+	}
+	if !found {
+		return 0
+	}
+	return last.Sub(first)
+}
+
+// trackAction оценивает количество шагов или гребков по средней каденции,
+// сообщённой файлом, умноженной на длительность тренировки.
+func trackAction(samples []trackSample, duration time.Duration) int {
+	sum, n := 0, 0
+	for _, s := range samples {
+		if !s.HasCadence {
+			continue
+		}
+		sum += s.Cadence
+		n++
+	}
+	if n == 0 {
+		return 0
+	}
+	avgCadence := float64(sum) / float64(n)
+	return int(avgCadence*duration.Minutes() + 0.5)
+}
+
+// trackDistance суммирует дистанцию, сообщённую файлом напрямую
+// (например, TCX DistanceMeters), и используется для заплывов.
+func trackDistance(samples []trackSample) float64 {
+	var max float64
+	for _, s := range samples {
+		if s.HasDistance && s.Distance > max {
+			max = s.Distance
+		}
+	}
+	return max
+}
+
+// gpxDoc — минимальное подмножество схемы GPX 1.1, достаточное для
+// восстановления тренировки: точки трека и необязательный профиль атлета
+// в расширениях метаданных.
+type gpxDoc struct {
+	XMLName  xml.Name `xml:"gpx"`
+	Metadata struct {
+		Extensions profileExtensions `xml:"extensions"`
+	} `xml:"metadata"`
+	Tracks []struct {
+		Type     string `xml:"type"`
+		Segments []struct {
+			Points []gpxPoint `xml:"trkpt"`
+		} `xml:"trkseg"`
+	} `xml:"trk"`
+}
+
+type gpxPoint struct {
+	Time       string `xml:"time"`
+	Extensions struct {
+		TrackPointExtension struct {
+			Cadence int `xml:"cad"`
+		} `xml:"TrackPointExtension"`
+	} `xml:"extensions"`
+}
+
+// ImportGPX читает GPX-файл (экспорт Strava/Garmin/MapMyRide) и строит из
+// его треков тренировки Running, Walking или Swimming.
+func ImportGPX(r io.Reader) ([]CaloriesCalculator, error) {
+	var doc gpxDoc
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("importer: разбор GPX: %w", err)
+	}
+
+	var result []CaloriesCalculator
+	for _, trk := range doc.Tracks {
+		var samples []trackSample
+		for _, seg := range trk.Segments {
+			for _, pt := range seg.Points {
+				s := trackSample{}
+				if t, err := time.Parse(time.RFC3339, pt.Time); err == nil {
+					s.Time, s.HasTime = t, true
+				}
+				if cad := pt.Extensions.TrackPointExtension.Cadence; cad != 0 {
+					s.Cadence, s.HasCadence = cad, true
+				}
+				samples = append(samples, s)
+			}
+		}
+		trainings, err := buildTrainings(strings.ToLower(trk.Type), samples, doc.Metadata.Extensions)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, trainings...)
+	}
+	return result, nil
+}
+
+// tcxDoc — минимальное подмножество схемы Garmin Training Center XML.
+type tcxDoc struct {
+	XMLName    xml.Name `xml:"TrainingCenterDatabase"`
+	Activities struct {
+		Activity []struct {
+			Sport string `xml:"Sport,attr"`
+			Laps  []struct {
+				Track struct {
+					Trackpoint []tcxPoint `xml:"Trackpoint"`
+				} `xml:"Track"`
+			} `xml:"Lap"`
+		} `xml:"Activity"`
+	} `xml:"Activities"`
+	Author profileExtensions `xml:"Author"`
+}
+
+type tcxPoint struct {
+	Time           string  `xml:"Time"`
+	DistanceMeters float64 `xml:"DistanceMeters"`
+	Cadence        int     `xml:"Cadence"`
+}
+
+// ImportTCX читает TCX-файл и строит из его активностей тренировки
+// Running, Walking или Swimming.
+func ImportTCX(r io.Reader) ([]CaloriesCalculator, error) {
+	var doc tcxDoc
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("importer: разбор TCX: %w", err)
+	}
+
+	var result []CaloriesCalculator
+	for _, act := range doc.Activities.Activity {
+		var samples []trackSample
+		for _, lap := range act.Laps {
+			for _, pt := range lap.Track.Trackpoint {
+				s := trackSample{}
+				if t, err := time.Parse(time.RFC3339, pt.Time); err == nil {
+					s.Time, s.HasTime = t, true
+				}
+				if pt.Cadence != 0 {
+					s.Cadence, s.HasCadence = pt.Cadence, true
+				}
+				if pt.DistanceMeters != 0 {
+					s.Distance, s.HasDistance = pt.DistanceMeters, true
+				}
+				samples = append(samples, s)
+			}
+		}
+		trainings, err := buildTrainings(strings.ToLower(act.Sport), samples, doc.Author)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, trainings...)
+	}
+	return result, nil
+}
+
+// fitEpoch — начало отсчёта времени в формате ANT/Garmin FIT
+// (1989-12-31T00:00:00Z), используется для перевода поля timestamp.
+var fitEpoch = time.Date(1989, 12, 31, 0, 0, 0, 0, time.UTC)
+
+const fitRecordMessage = 20 // номер глобального сообщения "record"
+
+// fitFieldDef описывает одно поле в определении FIT-сообщения (обычное
+// или разработчика — для последнего Num хранит dev_data_index, а не
+// номер поля, поскольку при чтении записи нам важен только его размер).
+type fitFieldDef struct {
+	Num  byte
+	Size byte
+}
+
+// fitDefinition — разобранное определение локального FIT-сообщения.
+type fitDefinition struct {
+	GlobalMsg uint16
+	BigEndian bool
+	Fields    []fitFieldDef
+	DevFields []fitFieldDef
+}
+
+// applyCompressedTimestamp раскрывает 5-битное смещение времени из
+// заголовка записи со сжатым таймстампом относительно последней полной
+// метки времени, с учётом переполнения 5-битного счётчика.
+func applyCompressedTimestamp(last uint32, offset byte) uint32 {
+	lastOffset := last & 0x1F
+	next := last - lastOffset + uint32(offset)
+	if uint32(offset) < lastOffset {
+		next += 0x20
+	}
+	return next
+}
+
+// readFitDataMessage читает одну запись данных согласно определению def
+// из lr, возвращает точку трека и, если запись несёт поле timestamp,
+// разобранное значение в секундах от fitEpoch.
+func readFitDataMessage(lr io.Reader, def fitDefinition) (trackSample, uint32, bool, error) {
+	var order binary.ByteOrder = binary.LittleEndian
+	if def.BigEndian {
+		order = binary.BigEndian
+	}
+
+	s := trackSample{}
+	var ts uint32
+	hasTs := false
+	for _, f := range def.Fields {
+		raw := make([]byte, f.Size)
+		if _, err := io.ReadFull(lr, raw); err != nil {
+			return s, 0, false, fmt.Errorf("importer: чтение значения FIT: %w", err)
+		}
+		if def.GlobalMsg != fitRecordMessage {
+			continue
+		}
+		switch f.Num {
+		case 253: // timestamp, секунды от fitEpoch
+			if f.Size == 4 {
+				ts = order.Uint32(raw)
+				hasTs = true
+				s.Time = fitEpoch.Add(time.Duration(ts) * time.Second)
+				s.HasTime = true
+			}
+		case 4: // cadence, rpm
+			if f.Size == 1 && raw[0] != 0xFF {
+				s.Cadence, s.HasCadence = int(raw[0]), true
+			}
+		case 5: // distance, см*100 -> м
+			if f.Size == 4 {
+				v := order.Uint32(raw)
+				if v != 0xFFFFFFFF {
+					s.Distance, s.HasDistance = float64(v)/100.0, true
+				}
+			}
+		}
+	}
+	// Поля разработчика нам не нужны, но должны быть прочитаны, иначе
+	// поток рассинхронизируется со следующей записью.
+	for _, f := range def.DevFields {
+		raw := make([]byte, f.Size)
+		if _, err := io.ReadFull(lr, raw); err != nil {
+			return s, 0, false, fmt.Errorf("importer: чтение поля разработчика FIT: %w", err)
+		}
+	}
+	return s, ts, hasTs, nil
+}
+
+// ImportFIT читает бинарный файл Garmin/ANT FIT и строит тренировки из его
+// записей типа "record" (timestamp, cadence, distance). Поддерживает как
+// обычные заголовки записей, так и заголовки со сжатым таймстампом и
+// определения с полями разработчика — оба варианта регулярно встречаются
+// в экспортах Garmin/Strava.
+func ImportFIT(r io.Reader) ([]CaloriesCalculator, error) {
+	header := make([]byte, 12)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, fmt.Errorf("importer: чтение заголовка FIT: %w", err)
+	}
+	headerSize := int(header[0])
+	if headerSize > 12 {
+		if _, err := io.CopyN(io.Discard, r, int64(headerSize-12)); err != nil {
+			return nil, fmt.Errorf("importer: чтение заголовка FIT: %w", err)
+		}
+	}
+	if string(header[8:12]) != ".FIT" {
+		return nil, fmt.Errorf("importer: не FIT-файл (нет сигнатуры .FIT)")
+	}
+	dataSize := binary.LittleEndian.Uint32(header[4:8])
+
+	lr := io.LimitReader(r, int64(dataSize))
+	defs := make(map[byte]fitDefinition)
+	var samples []trackSample
+	var lastTimestamp uint32
+	haveTimestamp := false
+
+	for {
+		var recHeader [1]byte
+		if _, err := io.ReadFull(lr, recHeader[:]); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, fmt.Errorf("importer: чтение записи FIT: %w", err)
+		}
+		h := recHeader[0]
+
+		if h&0x80 != 0 { // заголовок со сжатым таймстампом
+			local := (h >> 5) & 0x03
+			timeOffset := h & 0x1F
+			def, ok := defs[local]
+			if !ok {
+				return nil, fmt.Errorf("importer: данные FIT без определения сообщения")
+			}
+			if !haveTimestamp {
+				return nil, fmt.Errorf("importer: сжатый таймстамп FIT без предшествующей полной метки времени")
+			}
+			s, _, _, err := readFitDataMessage(lr, def)
+			if err != nil {
+				return nil, err
+			}
+			lastTimestamp = applyCompressedTimestamp(lastTimestamp, timeOffset)
+			s.Time = fitEpoch.Add(time.Duration(lastTimestamp) * time.Second)
+			s.HasTime = true
+			samples = append(samples, s)
+			continue
+		}
+
+		local := h & 0x0F
+
+		if h&0x40 != 0 { // определение сообщения
+			hasDevFields := h&0x20 != 0
+			buf := make([]byte, 5)
+			if _, err := io.ReadFull(lr, buf); err != nil {
+				return nil, fmt.Errorf("importer: чтение определения FIT: %w", err)
+			}
+			bigEndian := buf[1] == 1
+			var order binary.ByteOrder = binary.LittleEndian
+			if bigEndian {
+				order = binary.BigEndian
+			}
+			globalMsg := order.Uint16(buf[2:4])
+			numFields := int(buf[4])
+			fields := make([]fitFieldDef, 0, numFields)
+			fieldBytes := make([]byte, 3*numFields)
+			if _, err := io.ReadFull(lr, fieldBytes); err != nil {
+				return nil, fmt.Errorf("importer: чтение полей FIT: %w", err)
+			}
+			for i := 0; i < numFields; i++ {
+				fields = append(fields, fitFieldDef{
+					Num:  fieldBytes[3*i],
+					Size: fieldBytes[3*i+1],
+				})
+			}
+
+			var devFields []fitFieldDef
+			if hasDevFields {
+				var numDevBuf [1]byte
+				if _, err := io.ReadFull(lr, numDevBuf[:]); err != nil {
+					return nil, fmt.Errorf("importer: чтение числа полей разработчика FIT: %w", err)
+				}
+				numDevFields := int(numDevBuf[0])
+				devBytes := make([]byte, 3*numDevFields)
+				if _, err := io.ReadFull(lr, devBytes); err != nil {
+					return nil, fmt.Errorf("importer: чтение полей разработчика FIT: %w", err)
+				}
+				for i := 0; i < numDevFields; i++ {
+					devFields = append(devFields, fitFieldDef{
+						Num:  devBytes[3*i],
+						Size: devBytes[3*i+1],
+					})
+				}
+			}
+
+			defs[local] = fitDefinition{GlobalMsg: globalMsg, BigEndian: bigEndian, Fields: fields, DevFields: devFields}
+			continue
+		}
+
+		def, ok := defs[local]
+		if !ok {
+			return nil, fmt.Errorf("importer: данные FIT без определения сообщения")
+		}
+		s, ts, hasTs, err := readFitDataMessage(lr, def)
+		if err != nil {
+			return nil, err
+		}
+		if hasTs {
+			lastTimestamp = ts
+			haveTimestamp = true
+		}
+		samples = append(samples, s)
+	}
+
+	// FIT не несёт явного типа тренировки в сообщении record, поэтому по
+	// умолчанию разбираем файл как бег; прочие типы приходят через GPX/TCX.
+	return buildTrainings("running", samples, profileExtensions{})
+}
+
+// ImportFile определяет формат тренировки по расширению файла (.gpx,
+// .tcx, .fit) и делегирует разбор соответствующему импортёру.
+func ImportFile(path string) ([]CaloriesCalculator, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("importer: открытие файла: %w", err)
+	}
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".gpx":
+		return ImportGPX(f)
+	case ".tcx":
+		return ImportTCX(f)
+	case ".fit":
+		return ImportFIT(f)
+	default:
+		return nil, fmt.Errorf("importer: неизвестный формат файла %q", path)
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestImportGPXRunning(t *testing.T) {
+	const doc = `<?xml version="1.0"?>
+<gpx>
+  <metadata><extensions><Weight>80</Weight><Height>180</Height></extensions></metadata>
+  <trk>
+    <type>running</type>
+    <trkseg>
+      <trkpt lat="55.0" lon="37.0"><time>2024-01-01T10:00:00Z</time>
+        <extensions><TrackPointExtension><cad>170</cad></TrackPointExtension></extensions>
+      </trkpt>
+      <trkpt lat="55.0" lon="37.0"><time>2024-01-01T10:30:00Z</time>
+        <extensions><TrackPointExtension><cad>170</cad></TrackPointExtension></extensions>
+      </trkpt>
+    </trkseg>
+  </trk>
+</gpx>`
+
+	trainings, err := ImportGPX(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("ImportGPX: %v", err)
+	}
+	if len(trainings) != 1 {
+		t.Fatalf("got %d trainings, want 1", len(trainings))
+	}
+	r, ok := trainings[0].(Running)
+	if !ok {
+		t.Fatalf("got %T, want Running", trainings[0])
+	}
+	if r.Duration != 30*time.Minute {
+		t.Errorf("Duration = %v, want 30m", r.Duration)
+	}
+	if r.Weight != 80 {
+		t.Errorf("Weight = %v, want 80", r.Weight)
+	}
+	if r.Action != 170*30 {
+		t.Errorf("Action = %d, want %d", r.Action, 170*30)
+	}
+}
+
+func TestImportTCXSwimming(t *testing.T) {
+	const doc = `<?xml version="1.0"?>
+<TrainingCenterDatabase>
+  <Activities>
+    <Activity Sport="Swimming">
+      <Lap><Track>
+        <Trackpoint><Time>2024-01-01T10:00:00Z</Time><DistanceMeters>0</DistanceMeters></Trackpoint>
+        <Trackpoint><Time>2024-01-01T10:40:00Z</Time><DistanceMeters>2000</DistanceMeters></Trackpoint>
+      </Track></Lap>
+    </Activity>
+  </Activities>
+  <Author><Weight>75</Weight><PoolLength>50</PoolLength></Author>
+</TrainingCenterDatabase>`
+
+	trainings, err := ImportTCX(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("ImportTCX: %v", err)
+	}
+	if len(trainings) != 1 {
+		t.Fatalf("got %d trainings, want 1", len(trainings))
+	}
+	s, ok := trainings[0].(Swimming)
+	if !ok {
+		t.Fatalf("got %T, want Swimming", trainings[0])
+	}
+	if s.Weight != 75 {
+		t.Errorf("Weight = %v, want 75", s.Weight)
+	}
+	if s.LengthPool != 50 {
+		t.Errorf("LengthPool = %d, want 50", s.LengthPool)
+	}
+	if s.CountPool != 40 {
+		t.Errorf("CountPool = %d, want 40", s.CountPool)
+	}
+	if s.Duration != 40*time.Minute {
+		t.Errorf("Duration = %v, want 40m", s.Duration)
+	}
+}
+
+// fitBuilder assembles a minimal, hand-crafted FIT byte stream for tests,
+// covering plain data messages, compressed-timestamp headers and
+// definitions with developer fields.
+type fitBuilder struct {
+	buf bytes.Buffer
+}
+
+func (b *fitBuilder) definition(local byte, globalMsg uint16, devFields bool, fields [][3]byte, devs [][2]byte) {
+	header := byte(0x40) | local
+	if devFields {
+		header |= 0x20
+	}
+	b.buf.WriteByte(header)
+	b.buf.WriteByte(0) // reserved
+	b.buf.WriteByte(0) // architecture: little-endian
+	var gm [2]byte
+	binary.LittleEndian.PutUint16(gm[:], globalMsg)
+	b.buf.Write(gm[:])
+	b.buf.WriteByte(byte(len(fields)))
+	for _, f := range fields {
+		b.buf.Write(f[:])
+	}
+	if devFields {
+		b.buf.WriteByte(byte(len(devs)))
+		for _, d := range devs {
+			b.buf.WriteByte(d[0])
+			b.buf.WriteByte(d[1])
+			b.buf.WriteByte(0) // dev_data_index
+		}
+	}
+}
+
+func (b *fitBuilder) dataNormal(local byte, timestamp uint32, cadence byte, distanceCm uint32, dev []byte) {
+	b.buf.WriteByte(local & 0x0F)
+	var ts, dist [4]byte
+	binary.LittleEndian.PutUint32(ts[:], timestamp)
+	binary.LittleEndian.PutUint32(dist[:], distanceCm)
+	b.buf.Write(ts[:])
+	b.buf.WriteByte(cadence)
+	b.buf.Write(dist[:])
+	b.buf.Write(dev)
+}
+
+func (b *fitBuilder) dataCompressed(local, offset byte, cadence byte, distanceCm uint32) {
+	header := byte(0x80) | (local&0x03)<<5 | (offset & 0x1F)
+	b.buf.WriteByte(header)
+	var ignoredTs, dist [4]byte
+	binary.LittleEndian.PutUint32(ignoredTs[:], 0xFFFFFFFF)
+	binary.LittleEndian.PutUint32(dist[:], distanceCm)
+	b.buf.Write(ignoredTs[:])
+	b.buf.WriteByte(cadence)
+	b.buf.Write(dist[:])
+}
+
+func (b *fitBuilder) bytes() []byte {
+	var file bytes.Buffer
+	header := make([]byte, 12)
+	header[0] = 12
+	header[1] = 0x10
+	binary.LittleEndian.PutUint32(header[4:8], uint32(b.buf.Len()))
+	copy(header[8:12], ".FIT")
+	file.Write(header)
+	file.Write(b.buf.Bytes())
+	return file.Bytes()
+}
+
+func TestImportFITNormalAndCompressedTimestamps(t *testing.T) {
+	var b fitBuilder
+	// record message (global 20): timestamp(253,4), cadence(4,1), distance(5,4)
+	recordFields := [][3]byte{{253, 4, 0x86}, {4, 1, 0x02}, {5, 4, 0x86}}
+	b.definition(0, fitRecordMessage, false, recordFields, nil)
+	b.dataNormal(0, 1000000, 80, 0, nil)
+	b.dataCompressed(0, 10, 85, 5000) // +10s, distance 50.00m
+
+	// second local message definition with a developer field, to prove
+	// the byte stream stays in sync when developer fields are present.
+	b.definition(1, fitRecordMessage, true, recordFields, [][2]byte{{0, 2}})
+	b.dataNormal(1, 1000030, 0, 0, []byte{0x00, 0x00})
+
+	trainings, err := ImportFIT(bytes.NewReader(b.bytes()))
+	if err != nil {
+		t.Fatalf("ImportFIT: %v", err)
+	}
+	if len(trainings) != 1 {
+		t.Fatalf("got %d trainings, want 1", len(trainings))
+	}
+	r, ok := trainings[0].(Running)
+	if !ok {
+		t.Fatalf("got %T, want Running", trainings[0])
+	}
+	if r.Duration != 30*time.Second {
+		t.Errorf("Duration = %v, want 30s", r.Duration)
+	}
+}
@@ -28,10 +28,24 @@ type Training struct {
 	LenStep      float64       // Длина одного шага или гребка
 	Duration     time.Duration // Длительность тренировки
 	Weight       float64       // Вес пользователя
+	Model        string        // Имя модели расчёта калорий, пусто — модель по умолчанию
+	Units        UnitSystem    // Система единиц для отображения в InfoMessage
+
+	// distanceOverride — дистанция, измеренная GPS-треком (км), если
+	// задана; имеет приоритет над Action*LenStep в distance().
+	distanceOverride *float64
+
+	HRSamples []HRSample // Пульс во время тренировки, по возрастанию времени
+	Zones     HRZones    // Пороги пульса атлета для расчёта зон и TRIMP
+	Age       int        // Возраст атлета, лет (для CaloriesFromHR)
+	Sex       Sex        // Пол атлета (для TRIMP и CaloriesFromHR)
 }
 
 // distance возвращает дистанцию в километрах
 func (t Training) distance() float64 {
+	if t.distanceOverride != nil {
+		return *t.distanceOverride
+	}
 	return float64(t.Action) * t.LenStep / MInKm
 }
 
@@ -51,28 +65,63 @@ func (t Training) Calories() float64 {
 
 // TrainingInfo формирует общую информацию о тренировке
 func (t Training) TrainingInfo() InfoMessage {
-	return InfoMessage{
+	info := InfoMessage{
 		TrainingType: t.TrainingType,
 		Duration:     t.Duration,
 		Distance:     t.distance(),
 		Speed:        t.meanSpeed(),
 		Calories:     t.Calories(),
+		HasDistance:  true,
+		Units:        t.Units,
 	}
+	if len(t.HRSamples) > 0 {
+		info.HasHR = true
+		info.CaloriesFromHR = t.CaloriesFromHR()
+	}
+	return info
 }
 
-// InfoMessage структура для отображения информации о тренировке
+// InfoMessage структура для отображения информации о тренировке.
+// HasDistance отличает тренировки с перемещением (бег, ходьба, плавание)
+// от силовых, для которых дистанция и скорость не имеют смысла. Units
+// определяет, в каких единицах показывать дистанцию и скорость. HasHR и
+// CaloriesFromHR позволяют сравнить механическую формулу калорий с
+// оценкой по данным пульса, если тренировка несёт HRSamples.
 type InfoMessage struct {
-	TrainingType string
-	Duration     time.Duration
-	Distance     float64
-	Speed        float64
-	Calories     float64
+	TrainingType   string
+	Duration       time.Duration
+	Distance       float64
+	Speed          float64
+	Calories       float64
+	HasDistance    bool
+	Units          UnitSystem
+	HasHR          bool
+	CaloriesFromHR float64
 }
 
 // String форматирует вывод информации о тренировке
 func (i InfoMessage) String() string {
-	return fmt.Sprintf("Тип тренировки: %s\nДлительность: %.1f минут\nДистанция: %.2f км\nСр. скорость: %.2f км/ч\nПотрачено ккал: %.2f",
-		i.TrainingType, i.Duration.Minutes(), i.Distance, i.Speed, i.Calories)
+	if !i.HasDistance {
+		s := fmt.Sprintf("Тип тренировки: %s\nДлительность: %.1f минут\nПотрачено ккал: %.2f",
+			i.TrainingType, i.Duration.Minutes(), i.Calories)
+		if i.HasHR {
+			s += fmt.Sprintf("\nКалории по ЧСС (Keytel): %.2f", i.CaloriesFromHR)
+		}
+		return s
+	}
+	distance, speed := i.Distance, i.Speed
+	distUnit, speedUnit := "км", "км/ч"
+	if i.Units == Imperial {
+		distance *= KmToMiles
+		speed *= KmToMiles
+		distUnit, speedUnit = "миль", "миль/ч"
+	}
+	s := fmt.Sprintf("Тип тренировки: %s\nДлительность: %.1f минут\nДистанция: %.2f %s\nСр. скорость: %.2f %s\nПотрачено ккал: %.2f",
+		i.TrainingType, i.Duration.Minutes(), distance, distUnit, speed, speedUnit, i.Calories)
+	if i.HasHR {
+		s += fmt.Sprintf("\nКалории по ЧСС (Keytel): %.2f", i.CaloriesFromHR)
+	}
+	return s
 }
 
 // CaloriesCalculator интерфейс для тренировок
@@ -88,8 +137,11 @@ type Running struct {
 
 // Calories рассчитывает калории для бега
 func (r Running) Calories() float64 {
-	return ((CaloriesMeanSpeedMultiplier*r.meanSpeed() + CaloriesMeanSpeedShift) *
-		r.Weight / MInKm * r.Duration.Hours() * MinsInHour)
+	return lookupModel("running", r.Model).Calories(CalorieInputs{
+		MeanSpeed: r.meanSpeed(),
+		Weight:    r.Weight,
+		Duration:  r.Duration,
+	})
 }
 
 // TrainingInfo возвращает информацию о тренировке Бег
@@ -105,14 +157,12 @@ type Walking struct {
 
 // Calories рассчитывает калории для ходьбы
 func (w Walking) Calories() float64 {
-	heightInMeters := w.Height / CmInM
-	if heightInMeters == 0 {
-		return 0
-	}
-	speedInMSec := w.meanSpeed() * KmHInMsec
-	return ((CaloriesWeightMultiplier*w.Weight +
-		(speedInMSec*speedInMSec/heightInMeters)*CaloriesSpeedHeightMultiplier*w.Weight) *
-		w.Duration.Hours() * MinsInHour)
+	return lookupModel("walking", w.Model).Calories(CalorieInputs{
+		MeanSpeed: w.meanSpeed(),
+		Weight:    w.Weight,
+		Height:    w.Height,
+		Duration:  w.Duration,
+	})
 }
 
 // TrainingInfo возвращает информацию о тренировке Ходьба
@@ -138,19 +188,60 @@ func (s Swimming) meanSpeed() float64 {
 
 // Calories рассчитывает калории для плавания
 func (s Swimming) Calories() float64 {
-	return (s.meanSpeed() + SwimmingCaloriesMeanSpeedShift) *
-		SwimmingCaloriesWeightMultiplier * s.Weight * s.Duration.Hours()
+	return lookupModel("swimming", s.Model).Calories(CalorieInputs{
+		MeanSpeed: s.meanSpeed(),
+		Weight:    s.Weight,
+		Duration:  s.Duration,
+	})
 }
 
 // TrainingInfo переопределяет информацию о тренировке Плавание
 func (s Swimming) TrainingInfo() InfoMessage {
-	return InfoMessage{
+	info := InfoMessage{
 		TrainingType: s.TrainingType,
 		Duration:     s.Duration,
 		Distance:     float64(s.LengthPool*s.CountPool) / MInKm,
 		Speed:        s.meanSpeed(),
 		Calories:     s.Calories(),
+		HasDistance:  true,
+		Units:        s.Units,
+	}
+	if len(s.HRSamples) > 0 {
+		info.HasHR = true
+		info.CaloriesFromHR = s.CaloriesFromHR()
+	}
+	return info
+}
+
+// Strength структура для силовой тренировки (подходы, повторения, отягощение)
+type Strength struct {
+	Training
+	Sets       int     // Количество подходов
+	Reps       int     // Количество повторений в подходе
+	LiftWeight float64 // Вес отягощения, кг
+	MET        float64 // Метаболический эквивалент упражнения
+}
+
+// Calories рассчитывает калории для силовой тренировки по формуле MET
+func (st Strength) Calories() float64 {
+	return st.MET * st.Weight * st.Duration.Hours()
+}
+
+// TrainingInfo переопределяет информацию о тренировке: силовая тренировка
+// не перемещается в пространстве, поэтому дистанция и скорость не нужны
+func (st Strength) TrainingInfo() InfoMessage {
+	info := InfoMessage{
+		TrainingType: st.TrainingType,
+		Duration:     st.Duration,
+		Calories:     st.Calories(),
+		HasDistance:  false,
+		Units:        st.Units,
 	}
+	if len(st.HRSamples) > 0 {
+		info.HasHR = true
+		info.CaloriesFromHR = st.CaloriesFromHR()
+	}
+	return info
 }
 
 // ReadData выводит информацию о тренировке, с учетом переопределенных калорий
@@ -196,7 +287,20 @@ func main() {
 		},
 	}
 
+	strength := Strength{
+		Training: Training{
+			TrainingType: "Силовая тренировка",
+			Duration:     45 * time.Minute,
+			Weight:       85,
+		},
+		Sets:       4,
+		Reps:       10,
+		LiftWeight: 60,
+		MET:        6.0,
+	}
+
 	fmt.Println(ReadData(swimming))
 	fmt.Println(ReadData(walking))
+	fmt.Println(ReadData(strength))
 	fmt.Println(ReadData(running))
 }
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStrengthCaloriesMETFormula(t *testing.T) {
+	st := Strength{
+		Training: Training{
+			TrainingType: "Силовая тренировка",
+			Duration:     45 * time.Minute,
+			Weight:       85,
+		},
+		Sets:       4,
+		Reps:       10,
+		LiftWeight: 60,
+		MET:        6,
+	}
+	want := st.MET * st.Weight * st.Duration.Hours()
+	if got := st.Calories(); math.Abs(got-want) > 1e-9 {
+		t.Errorf("Calories() = %v, want %v", got, want)
+	}
+}
+
+func TestStrengthStringOmitsDistanceAndSpeed(t *testing.T) {
+	st := Strength{
+		Training: Training{
+			TrainingType: "Силовая тренировка",
+			Duration:     45 * time.Minute,
+			Weight:       85,
+		},
+		Sets: 4,
+		Reps: 10,
+		MET:  6,
+	}
+	info := st.TrainingInfo()
+	if info.HasDistance {
+		t.Fatal("InfoMessage.HasDistance = true for Strength, want false")
+	}
+	s := info.String()
+	if strings.Contains(s, "Дистанция") || strings.Contains(s, "Ср. скорость") {
+		t.Errorf("String() = %q, should not mention distance/speed for a non-locomotion training", s)
+	}
+	if !strings.Contains(s, "Потрачено ккал") {
+		t.Errorf("String() = %q, should still report calories", s)
+	}
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AthleteProfile — сведения об атлете, общие для всех тренировок
+// сессии. Session подставляет Weight/Height в тренировки, у которых эти
+// поля не заданы (равны нулю), прежде чем считать калории или дистанцию.
+type AthleteProfile struct {
+	Weight float64 // кг
+	Height float64 // см
+}
+
+// Session объединяет несколько тренировок одного посещения вместе с
+// датой и профилем атлета, превращая ReadData из построчного принтера
+// в полноценный журнал, который можно сохранить или отправить на сервер.
+type Session struct {
+	Date      time.Time
+	Athlete   AthleteProfile
+	Trainings []CaloriesCalculator
+}
+
+// effectiveTrainings возвращает тренировки сессии с подставленными из
+// s.Athlete весом и ростом там, где сама тренировка их не несёт (Weight
+// или Height равны нулю).
+func (s Session) effectiveTrainings() []CaloriesCalculator {
+	if s.Athlete.Weight == 0 && s.Athlete.Height == 0 {
+		return s.Trainings
+	}
+	out := make([]CaloriesCalculator, len(s.Trainings))
+	for i, tr := range s.Trainings {
+		out[i] = applyAthleteDefaults(tr, s.Athlete)
+	}
+	return out
+}
+
+// applyAthleteDefaults дозаполняет Weight (и Height для Walking) в
+// тренировке из профиля атлета, если они не заданы.
+func applyAthleteDefaults(tr CaloriesCalculator, profile AthleteProfile) CaloriesCalculator {
+	switch v := tr.(type) {
+	case Running:
+		if v.Weight == 0 {
+			v.Weight = profile.Weight
+		}
+		return v
+	case Walking:
+		if v.Weight == 0 {
+			v.Weight = profile.Weight
+		}
+		if v.Height == 0 {
+			v.Height = profile.Height
+		}
+		return v
+	case Swimming:
+		if v.Weight == 0 {
+			v.Weight = profile.Weight
+		}
+		return v
+	case Strength:
+		if v.Weight == 0 {
+			v.Weight = profile.Weight
+		}
+		return v
+	default:
+		return tr
+	}
+}
+
+// TotalCalories суммирует калории всех тренировок сессии.
+func (s Session) TotalCalories() float64 {
+	var total float64
+	for _, tr := range s.effectiveTrainings() {
+		total += tr.Calories()
+	}
+	return total
+}
+
+// TotalDistance суммирует дистанцию всех тренировок сессии, в км.
+func (s Session) TotalDistance() float64 {
+	var total float64
+	for _, tr := range s.effectiveTrainings() {
+		total += tr.TrainingInfo().Distance
+	}
+	return total
+}
+
+// TotalDuration суммирует длительность всех тренировок сессии.
+func (s Session) TotalDuration() time.Duration {
+	var total time.Duration
+	for _, tr := range s.effectiveTrainings() {
+		total += tr.TrainingInfo().Duration
+	}
+	return total
+}
+
+// PerTypeBreakdown группирует тренировки сессии по TrainingType,
+// накапливая длительность, дистанцию и калории каждой группы.
+func (s Session) PerTypeBreakdown() map[string]InfoMessage {
+	result := make(map[string]InfoMessage)
+	for _, tr := range s.effectiveTrainings() {
+		info := tr.TrainingInfo()
+		info.Calories = tr.Calories()
+
+		agg, ok := result[info.TrainingType]
+		if !ok {
+			result[info.TrainingType] = info
+			continue
+		}
+		agg.Duration += info.Duration
+		agg.Distance += info.Distance
+		agg.Calories += info.Calories
+		if agg.HasDistance && agg.Duration > 0 {
+			agg.Speed = agg.Distance / agg.Duration.Hours()
+		}
+		result[info.TrainingType] = agg
+	}
+	return result
+}
+
+// Summary формирует человекочитаемый отчёт по всем тренировкам сессии.
+func (s Session) Summary() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Тренировки за %s\n\n", s.Date.Format("02.01.2006"))
+	for _, tr := range s.effectiveTrainings() {
+		b.WriteString(ReadData(tr))
+		b.WriteString("\n\n")
+	}
+	fmt.Fprintf(&b, "Итого: %.0f мин, %.2f км, %.2f ккал",
+		s.TotalDuration().Minutes(), s.TotalDistance(), s.TotalCalories())
+	return b.String()
+}
+
+// sessionEntry — представление одной тренировки в JSON-экспорте сессии,
+// по образцу сводки тренировки в протоколах носимых устройств.
+type sessionEntry struct {
+	Type        string  `json:"type"`
+	DurationMin float64 `json:"time"`
+	Calories    float64 `json:"calories"`
+	Distance    float64 `json:"distance,omitempty"`
+	Pace        float64 `json:"pace,omitempty"` // мин/км
+	PoolLength  int     `json:"pool_length,omitempty"`
+	CountPool   int     `json:"count_pool,omitempty"`
+	Sets        int     `json:"sets,omitempty"`
+	Reps        int     `json:"reps,omitempty"`
+}
+
+// sessionDoc — корневой документ JSON-экспорта сессии.
+type sessionDoc struct {
+	Date      string         `json:"date"`
+	Trainings []sessionEntry `json:"trainings"`
+}
+
+// MarshalJSON реализует json.Marshaler, превращая Session в документ со
+// стабильной схемой: тип, дистанция, время, калории, темп, а также
+// специфичные для плавания (длина дорожки, число дорожек) и силовых
+// тренировок (подходы, повторения) поля.
+func (s Session) MarshalJSON() ([]byte, error) {
+	trainings := s.effectiveTrainings()
+	entries := make([]sessionEntry, 0, len(trainings))
+	for _, tr := range trainings {
+		info := tr.TrainingInfo()
+		entry := sessionEntry{
+			Type:        info.TrainingType,
+			DurationMin: info.Duration.Minutes(),
+			Calories:    tr.Calories(),
+		}
+		if info.HasDistance {
+			entry.Distance = info.Distance
+			if info.Distance > 0 {
+				entry.Pace = info.Duration.Minutes() / info.Distance
+			}
+		}
+		switch v := tr.(type) {
+		case Swimming:
+			entry.PoolLength = v.LengthPool
+			entry.CountPool = v.CountPool
+		case Strength:
+			entry.Sets = v.Sets
+			entry.Reps = v.Reps
+		}
+		entries = append(entries, entry)
+	}
+	return json.Marshal(sessionDoc{
+		Date:      s.Date.Format(time.RFC3339),
+		Trainings: entries,
+	})
+}
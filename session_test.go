@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestSessionAthleteDefaultsApply(t *testing.T) {
+	s := Session{
+		Athlete: AthleteProfile{Weight: 70, Height: 180},
+		Trainings: []CaloriesCalculator{
+			Running{Training: Training{
+				TrainingType: "Бег",
+				Action:       5000,
+				LenStep:      LenStep,
+				Duration:     30 * time.Minute,
+			}},
+		},
+	}
+
+	if got := s.TotalCalories(); got <= 0 {
+		t.Fatalf("TotalCalories() = %v, want > 0 once Athlete.Weight fills in the missing weight", got)
+	}
+
+	// Without the Athlete fallback, Running.Calories() is 0 because
+	// Weight is unset; this proves effectiveTrainings() is actually wired
+	// into the public API rather than just documented.
+	bare := Session{Trainings: s.Trainings}
+	if got := bare.TotalCalories(); got != 0 {
+		t.Fatalf("bare session TotalCalories() = %v, want 0", got)
+	}
+}
+
+func TestSessionPerTypeBreakdown(t *testing.T) {
+	s := Session{
+		Trainings: []CaloriesCalculator{
+			Running{Training: Training{TrainingType: "Бег", Action: 5000, LenStep: LenStep, Duration: 30 * time.Minute, Weight: 80}},
+			Running{Training: Training{TrainingType: "Бег", Action: 5000, LenStep: LenStep, Duration: 30 * time.Minute, Weight: 80}},
+		},
+	}
+	breakdown := s.PerTypeBreakdown()
+	info, ok := breakdown["Бег"]
+	if !ok {
+		t.Fatalf("breakdown missing %q", "Бег")
+	}
+	if info.Duration != time.Hour {
+		t.Errorf("Duration = %v, want 1h", info.Duration)
+	}
+}
+
+func TestSessionMarshalJSON(t *testing.T) {
+	s := Session{
+		Date: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+		Trainings: []CaloriesCalculator{
+			Swimming{
+				Training:   Training{TrainingType: "Плавание", Duration: 40 * time.Minute, Weight: 75},
+				LengthPool: 50,
+				CountPool:  40,
+			},
+			Strength{
+				Training: Training{TrainingType: "Силовая тренировка", Duration: 45 * time.Minute, Weight: 85},
+				Sets:     4,
+				Reps:     10,
+				MET:      6,
+			},
+		},
+	}
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+
+	var doc sessionDoc
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+	if len(doc.Trainings) != 2 {
+		t.Fatalf("got %d trainings, want 2", len(doc.Trainings))
+	}
+	if doc.Trainings[0].PoolLength != 50 || doc.Trainings[0].CountPool != 40 {
+		t.Errorf("swimming entry = %+v, want pool_length=50 count_pool=40", doc.Trainings[0])
+	}
+	if doc.Trainings[1].Sets != 4 || doc.Trainings[1].Reps != 10 {
+		t.Errorf("strength entry = %+v, want sets=4 reps=10", doc.Trainings[1])
+	}
+}